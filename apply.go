@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ApplyUint64 reconstructs b from a, ops (as returned by Uint64 or
+// Uint64With) and inserts, the elements that were added on the b side, in
+// order. It is the inverse of Uint64: for deltas produced by diffing a
+// against some b, ApplyUint64(a, deltas, inserts) returns b again, where
+// inserts holds the elements of every OpB entry in deltas.
+//
+// It returns an error if ops don't account for exactly all of a and all of
+// inserts.
+func ApplyUint64(a []uint64, ops []Op, inserts []uint64) ([]uint64, error) {
+	out := make([]uint64, 0, len(a)+len(inserts))
+	ai, ii := 0, 0
+	for _, op := range ops {
+		switch {
+		case op != OpB:
+			if ai >= len(a) {
+				return nil, fmt.Errorf("diff: ops reference more of a than the %d elements given", len(a))
+			}
+			if op == OpEq {
+				out = append(out, a[ai])
+			}
+			ai++
+		default:
+			if ii >= len(inserts) {
+				return nil, fmt.Errorf("diff: ops reference more inserts than the %d given", len(inserts))
+			}
+			out = append(out, inserts[ii])
+			ii++
+		}
+	}
+	if ai != len(a) {
+		return nil, fmt.Errorf("diff: ops only consume %d of the %d elements of a", ai, len(a))
+	}
+	if ii != len(inserts) {
+		return nil, fmt.Errorf("diff: ops only consume %d of the %d inserts", ii, len(inserts))
+	}
+	return out, nil
+}
+
+// Apply reconstructs b from a and deltas, as returned by Text or Lines,
+// using each OpB delta's Text as the inserted content and validating that
+// every OpA/OpEq delta's Text actually occurs, in order, among a's lines (as
+// split by SplitLines).
+//
+// As with Lines, a trailing newline is not tracked: the returned bytes are
+// the matched and inserted lines joined by "\n", without a trailing one.
+//
+// To apply an actual unified-diff patch (rather than a []TextDelta) see
+// unified.Apply; it lives in that subpackage, rather than here as
+// ApplyUnified, since this package can't import unified without a cycle.
+func Apply(a []byte, deltas []TextDelta) ([]byte, error) {
+	var lines [][]byte
+	for rest := a; len(rest) > 0; {
+		tok, skip := SplitLines(rest)
+		lines = append(lines, rest[:tok])
+		rest = rest[tok+skip:]
+	}
+
+	var out [][]byte
+	for _, d := range deltas {
+		if d.Op != OpB {
+			if len(lines) == 0 {
+				return nil, fmt.Errorf("diff: delta %q has no corresponding line left in a", d.Text)
+			}
+			if !bytes.Equal(lines[0], d.Text) {
+				return nil, fmt.Errorf("diff: delta %q does not match line %q in a", d.Text, lines[0])
+			}
+			lines = lines[1:]
+		}
+		if d.Op != OpA {
+			out = append(out, d.Text)
+		}
+	}
+	if len(lines) != 0 {
+		return nil, fmt.Errorf("diff: a has %d line(s) left over after applying deltas", len(lines))
+	}
+	return bytes.Join(out, []byte("\n")), nil
+}
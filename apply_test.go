@@ -0,0 +1,93 @@
+package diff_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Merovius/diff"
+)
+
+func TestApplyUint64(t *testing.T) {
+	tcs := []struct {
+		a       []uint64
+		b       []uint64
+		wantErr bool
+	}{
+		{nil, nil, false},
+		{[]uint64{1, 2, 3}, []uint64{1, 2, 3}, false},
+		{[]uint64{1, 2, 3}, []uint64{1, 4, 3}, false},
+		{[]uint64{1, 2, 3}, []uint64{4, 5, 6}, false},
+		{[]uint64{1, 2, 3, 4, 5}, []uint64{0, 2, 3, 9, 5}, false},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			ops := diff.Uint64(tc.a, tc.b)
+			var inserts []uint64
+			bi := 0
+			for _, op := range ops {
+				if op == diff.OpB {
+					inserts = append(inserts, tc.b[bi])
+				}
+				if op != diff.OpA {
+					bi++
+				}
+			}
+			got, err := diff.ApplyUint64(tc.a, ops, inserts)
+			if err != nil {
+				t.Fatalf("ApplyUint64(%v, %v, %v) = _, %v, want nil error", tc.a, ops, inserts, err)
+			}
+			if len(got) == 0 && len(tc.b) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.b) {
+				t.Errorf("ApplyUint64(%v, %v, %v) = %v, want %v", tc.a, ops, inserts, got, tc.b)
+			}
+		})
+	}
+
+	t.Run("bad inserts", func(t *testing.T) {
+		ops := diff.Uint64([]uint64{1, 2}, []uint64{1, 3})
+		if _, err := diff.ApplyUint64([]uint64{1, 2}, ops, nil); err == nil {
+			t.Error("ApplyUint64 with too few inserts = nil error, want non-nil")
+		}
+	})
+}
+
+func TestApply(t *testing.T) {
+	tcs := []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"a\nb\nc\n", "a\nb\nc\n"},
+		{"a\nb\nc\n", "a\nx\nc\n"},
+		{"a\nb\nc\n", ""},
+		{"", "a\nb\nc\n"},
+		{"a\nb\nc\n", "z\na\nb\nc\ny\n"},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			a, b := []byte(tc.a), []byte(tc.b)
+			deltas := diff.Lines(a, b)
+			got, err := diff.Apply(a, deltas)
+			if err != nil {
+				t.Fatalf("Apply(%q, Lines(...)) = _, %v, want nil error", a, err)
+			}
+			// Apply's contract doesn't track a trailing newline (like Lines
+			// itself doesn't), so the expected result is b's lines without one.
+			wantBytes := b
+			if len(wantBytes) > 0 && wantBytes[len(wantBytes)-1] == '\n' {
+				wantBytes = wantBytes[:len(wantBytes)-1]
+			}
+			if string(got) != string(wantBytes) {
+				t.Errorf("Apply(%q, Lines(%q, %q)) = %q, want %q", a, a, b, got, wantBytes)
+			}
+		})
+	}
+
+	t.Run("mismatched a", func(t *testing.T) {
+		deltas := diff.Lines([]byte("a\nb\n"), []byte("a\nc\n"))
+		if _, err := diff.Apply([]byte("a\nz\n"), deltas); err == nil {
+			t.Error("Apply with mismatched a = nil error, want non-nil")
+		}
+	})
+}
@@ -40,8 +40,14 @@ func Uint64(a, b []uint64) []Op {
 		suffix = append(suffix, OpEq)
 		a, b = a[:len(a)-1], b[:len(b)-1]
 	}
+	// suffix was built by appending as a and b were trimmed from the back,
+	// so it's in reverse order; put it back before appending it to the
+	// result below. (Every element is OpEq, so this reverses indices, not
+	// values — but it must index into suffix itself, not the now-shorter
+	// a, which an earlier version of this loop did, panicking whenever
+	// len(suffix) > len(a).)
 	for i := 0; i < len(suffix)/2; i++ {
-		a[i], a[len(a)-i-1] = a[len(a)-i-1], a[i]
+		suffix[i], suffix[len(suffix)-i-1] = suffix[len(suffix)-i-1], suffix[i]
 	}
 	if len(a) == 0 {
 		out := make([]Op, len(b))
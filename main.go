@@ -16,6 +16,8 @@ import (
 	"github.com/Merovius/diff"
 )
 
+var words = flag.Bool("words", false, "highlight intra-line word changes")
+
 func main() {
 	log.SetFlags(0)
 	flag.Parse()
@@ -31,7 +33,13 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	colorize(diff.LineDiff(a, b))
+
+	d := diff.Lines(a, b)
+	if *words {
+		colorizeRefined(diff.Refine(d, diff.RefineOptions{}))
+		return
+	}
+	colorize(d)
 }
 
 func colorize(d []diff.TextDelta) {
@@ -45,3 +53,53 @@ func colorize(d []diff.TextDelta) {
 		fmt.Printf("%s %s\n", prefix[δ.Op], δ.Text)
 	}
 }
+
+// colorizeRefined renders the output of diff.Refine, highlighting (in
+// reverse video) the sub-line spans that were actually added or removed
+// within the usual red/green +/- prefix.
+func colorizeRefined(d []diff.RefinedDelta) {
+	const (
+		red       = "\033[31m"
+		green     = "\033[32m"
+		highlight = "\033[7m"
+		reset     = "\033[0m"
+	)
+	for _, δ := range d {
+		if isUnchanged(δ) {
+			fmt.Printf("%s %s\n", reset, δ.A[0].Text)
+			continue
+		}
+		if len(δ.A) > 0 {
+			fmt.Printf("%s-%s%s\n", red, renderSpans(δ.A, highlight, red), reset)
+		}
+		if len(δ.B) > 0 {
+			fmt.Printf("%s+%s%s\n", green, renderSpans(δ.B, highlight, green), reset)
+		}
+	}
+}
+
+func isUnchanged(δ diff.RefinedDelta) bool {
+	for _, s := range δ.A {
+		if s.Op != diff.OpEq {
+			return false
+		}
+	}
+	for _, s := range δ.B {
+		if s.Op != diff.OpEq {
+			return false
+		}
+	}
+	return len(δ.A) > 0
+}
+
+func renderSpans(spans []diff.Span, highlight, normal string) string {
+	var s string
+	for _, span := range spans {
+		if span.Op == diff.OpEq {
+			s += normal + string(span.Text)
+		} else {
+			s += highlight + string(span.Text) + normal
+		}
+	}
+	return s
+}
@@ -0,0 +1,177 @@
+package diff
+
+import "sort"
+
+// Block describes a contiguous run of elements that a and b have in common,
+// as returned by Matcher.MatchingBlocks. AStart and BStart are the indices
+// into a and b respectively where the run starts, and Len is its length.
+type Block struct {
+	AStart, BStart, Len int
+}
+
+// Matcher compares two sequences and answers questions about their
+// similarity, along the lines of Python's difflib.SequenceMatcher.
+type Matcher struct {
+	a, b   []uint64
+	blocks []Block
+}
+
+// NewMatcher returns a Matcher comparing a and b.
+func NewMatcher(a, b []uint64) *Matcher {
+	return &Matcher{a: a, b: b}
+}
+
+// NewTextMatcher is like NewMatcher, but first splits and hashes a and b. s
+// and h are as in Text: if s is nil, SplitLines is used; if h is nil,
+// DefaultHash is used.
+func NewTextMatcher(a, b []byte, s SplitFunc, h HashFunc) *Matcher {
+	if s == nil {
+		s = SplitLines
+	}
+	if h == nil {
+		h = DefaultHash()
+	}
+	_, ha := tokenize(a, h, s)
+	_, hb := tokenize(b, h, s)
+	return NewMatcher(ha, hb)
+}
+
+// MatchingBlocks returns the maximal contiguous matching blocks between a
+// and b, in order, followed by a zero-length sentinel block at
+// {len(a), len(b), 0}.
+func (m *Matcher) MatchingBlocks() []Block {
+	if m.blocks == nil {
+		m.blocks = matchingBlocks(m.a, m.b)
+	}
+	return m.blocks
+}
+
+func matchingBlocks(a, b []uint64) []Block {
+	var blocks []Block
+	var cur *Block
+	ai, bi := 0, 0
+	for _, op := range Uint64(a, b) {
+		if op == OpEq {
+			if cur == nil {
+				cur = &Block{AStart: ai, BStart: bi}
+			}
+			cur.Len++
+			ai++
+			bi++
+			continue
+		}
+		if cur != nil {
+			blocks = append(blocks, *cur)
+			cur = nil
+		}
+		if op == OpA {
+			ai++
+		} else {
+			bi++
+		}
+	}
+	if cur != nil {
+		blocks = append(blocks, *cur)
+	}
+	return append(blocks, Block{AStart: len(a), BStart: len(b)})
+}
+
+// Ratio returns a measure of the similarity of a and b, as 2.0*M/T, where T
+// is the number of elements in both sequences and M is the number of
+// matches (the summed length of the MatchingBlocks, excluding the
+// sentinel). Ratio is 1.0 if a and b are equal and 0.0 if they share no
+// elements at all.
+func (m *Matcher) Ratio() float64 {
+	matches := 0
+	for _, b := range m.MatchingBlocks() {
+		matches += b.Len
+	}
+	return calcRatio(matches, len(m.a), len(m.b))
+}
+
+// QuickRatio returns an upper bound on Ratio, computed by comparing the
+// multiset of elements of a and b, ignoring order. It is cheaper to compute
+// than Ratio, so is useful as a pre-filter when comparing many candidates.
+func (m *Matcher) QuickRatio() float64 {
+	full := make(map[uint64]int, len(m.b))
+	for _, v := range m.b {
+		full[v]++
+	}
+	avail := make(map[uint64]int)
+	matches := 0
+	for _, v := range m.a {
+		n, ok := avail[v]
+		if !ok {
+			n = full[v]
+		}
+		avail[v] = n - 1
+		if n > 0 {
+			matches++
+		}
+	}
+	return calcRatio(matches, len(m.a), len(m.b))
+}
+
+// RealQuickRatio returns an upper bound on Ratio (and on QuickRatio),
+// computed in O(1) from the lengths of a and b alone.
+func (m *Matcher) RealQuickRatio() float64 {
+	n := len(m.a)
+	if len(m.b) < n {
+		n = len(m.b)
+	}
+	return calcRatio(n, len(m.a), len(m.b))
+}
+
+func calcRatio(matches, la, lb int) float64 {
+	if la+lb == 0 {
+		return 1
+	}
+	return 2 * float64(matches) / float64(la+lb)
+}
+
+// GetCloseMatches returns up to n of candidates that are most similar to
+// word, best match first, keeping only those with a Ratio (computed at the
+// granularity of runes) of at least cutoff.
+//
+// This is a package-level function, not a Matcher method, matching
+// difflib's own module-level get_close_matches: word is compared against
+// many independent candidates, each needing its own Matcher internally, so
+// there's no single pair of sequences for a Matcher receiver to hold.
+func GetCloseMatches(word []byte, candidates [][]byte, n int, cutoff float64) [][]byte {
+	w := runeSeq(word)
+
+	type scored struct {
+		s     []byte
+		ratio float64
+	}
+	var result []scored
+	for _, c := range candidates {
+		m := NewMatcher(runeSeq(c), w)
+		if m.RealQuickRatio() < cutoff || m.QuickRatio() < cutoff {
+			continue
+		}
+		if r := m.Ratio(); r >= cutoff {
+			result = append(result, scored{c, r})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ratio > result[j].ratio
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	out := make([][]byte, len(result))
+	for i, r := range result {
+		out[i] = r.s
+	}
+	return out
+}
+
+func runeSeq(b []byte) []uint64 {
+	var out []uint64
+	for _, r := range string(b) {
+		out = append(out, uint64(r))
+	}
+	return out
+}
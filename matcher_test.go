@@ -0,0 +1,67 @@
+package diff_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Merovius/diff"
+)
+
+func TestMatchingBlocks(t *testing.T) {
+	tcs := []struct {
+		a, b []uint64
+		want []diff.Block
+	}{
+		{nil, nil, []diff.Block{{0, 0, 0}}},
+		{[]uint64{1, 2, 3}, []uint64{1, 2, 3}, []diff.Block{{0, 0, 3}, {3, 3, 0}}},
+		{[]uint64{1, 2, 3}, []uint64{4, 5, 6}, []diff.Block{{3, 3, 0}}},
+		{
+			[]uint64{1, 2, 3, 4, 5},
+			[]uint64{0, 2, 3, 9, 5},
+			[]diff.Block{{1, 1, 2}, {4, 4, 1}, {5, 5, 0}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			got := diff.NewMatcher(tc.a, tc.b).MatchingBlocks()
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("MatchingBlocks(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRatio(t *testing.T) {
+	tcs := []struct {
+		a, b []uint64
+		want float64
+	}{
+		{nil, nil, 1},
+		{[]uint64{1, 2, 3}, []uint64{1, 2, 3}, 1},
+		{[]uint64{1, 2, 3}, []uint64{4, 5, 6}, 0},
+		{[]uint64{1, 2, 3, 4, 5}, []uint64{0, 2, 3, 9, 5}, 0.6},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			m := diff.NewMatcher(tc.a, tc.b)
+			if got := m.Ratio(); got != tc.want {
+				t.Errorf("Ratio(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+			if got := m.QuickRatio(); got < m.Ratio() {
+				t.Errorf("QuickRatio(%v, %v) = %v, want >= Ratio() = %v", tc.a, tc.b, got, m.Ratio())
+			}
+			if got := m.RealQuickRatio(); got < m.QuickRatio() {
+				t.Errorf("RealQuickRatio(%v, %v) = %v, want >= QuickRatio() = %v", tc.a, tc.b, got, m.QuickRatio())
+			}
+		})
+	}
+}
+
+func TestGetCloseMatches(t *testing.T) {
+	candidates := [][]byte{[]byte("appel"), []byte("apple"), []byte("peach"), []byte("puppy")}
+	got := diff.GetCloseMatches([]byte("appel"), candidates, 3, 0.6)
+	want := [][]byte{[]byte("appel"), []byte("apple")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCloseMatches(%q) = %q, want %q", "appel", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package diff_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Merovius/diff"
+)
+
+func TestMyers(t *testing.T) {
+	tcs := []struct {
+		a       []uint64
+		b       []uint64
+		wantLen int
+	}{
+		{[]uint64{}, []uint64{}, 0},
+		{[]uint64{}, []uint64{0}, 0},
+		{[]uint64{0}, []uint64{}, 0},
+		{[]uint64{0}, []uint64{0}, 1},
+		{[]uint64{0}, []uint64{1}, 0},
+		{[]uint64{0}, []uint64{0, 1}, 1},
+		{[]uint64{10, 20, 30, 40}, []uint64{1, 10, 20, 25, 40, 45}, 3},
+		{[]uint64{2, 4, 6}, []uint64{1, 2, 3, 4, 5}, 2},
+		{[]uint64{1, 1, 1, 3, 4, 4}, []uint64{0, 1, 0, 1, 0, 3, 1, 4, 5, 4, 6}, 5},
+		{[]uint64{23, 42}, []uint64{23, 23, 42}, 2},
+		{[]uint64{42, 23}, []uint64{42, 42, 23}, 2},
+	}
+
+	opts := diff.Options{Algorithm: diff.Myers}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			ac := append([]uint64{}, tc.a...)
+			bc := append([]uint64{}, tc.b...)
+			got := diff.Uint64With(tc.a, tc.b, opts)
+			if !sameSeq(tc.a, ac) {
+				t.Fatalf("Uint64With modified a")
+			}
+			if !sameSeq(tc.b, bc) {
+				t.Fatalf("Uint64With modified b")
+			}
+			if n := commonLength(got); n != tc.wantLen {
+				t.Fatalf("Uint64With(%v, %v) = %v, common sequence of length %d, want %d", tc.a, tc.b, got, n, tc.wantLen)
+			}
+			var gotA, gotB []uint64
+			for _, o := range got {
+				if o < diff.OpB {
+					gotA, ac = append(gotA, ac[0]), ac[1:]
+				}
+				if o > diff.OpA {
+					gotB, bc = append(gotB, bc[0]), bc[1:]
+				}
+			}
+			if !sameSeq(tc.a, gotA) || !sameSeq(tc.b, gotB) {
+				t.Fatalf("Uint64With(%v, %v) = %v, restores to wrong sequences: a=%v b=%v", tc.a, tc.b, got, gotA, gotB)
+			}
+		})
+	}
+}
+
+// FuzzMyers asserts that Myers and HuntMcIlroy always agree on the length of
+// the longest common subsequence, even though the edit scripts they produce
+// may differ. b is derived from a by applying a number of random edits, like
+// two revisions of the same source file would be, rather than drawn
+// independently: the latter would mostly produce disjoint sequences with no
+// interesting common subsequence to compare.
+func FuzzMyers(f *testing.F) {
+	f.Add(int64(0), 20, 5)
+	f.Add(int64(1), 0, 0)
+	f.Add(int64(2), 5, 20)
+	f.Fuzz(func(t *testing.T, seed int64, na, edits int) {
+		if na < 0 || edits < 0 || na > 200 || edits > 200 {
+			t.Skip("out of range")
+		}
+		rnd := rand.New(rand.NewSource(seed))
+		a := randSeq(rnd, na)
+		b := randEdit(rnd, a, edits)
+
+		wantLen := commonLength(diff.Uint64(a, b))
+		gotLen := commonLength(diff.Uint64With(a, b, diff.Options{Algorithm: diff.Myers}))
+		if gotLen != wantLen {
+			t.Fatalf("Myers and HuntMcIlroy disagree for a=%v, b=%v: %d != %d", a, b, gotLen, wantLen)
+		}
+	})
+}
+
+func randSeq(rnd *rand.Rand, n int) []uint64 {
+	s := make([]uint64, n)
+	for i := range s {
+		s[i] = rnd.Uint64()
+	}
+	return s
+}
+
+// randEdit returns a copy of a with n random insertions, deletions and
+// substitutions applied.
+func randEdit(rnd *rand.Rand, a []uint64, n int) []uint64 {
+	b := append([]uint64{}, a...)
+	for i := 0; i < n; i++ {
+		if len(b) == 0 {
+			b = append(b, rnd.Uint64())
+			continue
+		}
+		idx := rnd.Intn(len(b))
+		switch rnd.Intn(3) {
+		case 0:
+			b = append(b[:idx], b[idx+1:]...)
+		case 1:
+			b = append(b[:idx:idx], append([]uint64{rnd.Uint64()}, b[idx:]...)...)
+		case 2:
+			b[idx] = rnd.Uint64()
+		}
+	}
+	return b
+}
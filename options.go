@@ -0,0 +1,64 @@
+package diff
+
+// Algorithm selects the diff engine used by the *With functions.
+type Algorithm int
+
+const (
+	// HuntMcIlroy selects the algorithm implemented by Uint64. It is the
+	// zero value and thus the default.
+	HuntMcIlroy Algorithm = iota
+	// Myers selects Myers' O(ND) algorithm, as used by git and Go's own
+	// tooling. It tends to be considerably faster than HuntMcIlroy when a
+	// and b are similar, which is the common case when diffing source code.
+	Myers
+)
+
+// Options configures the *With variants of the diff functions.
+type Options struct {
+	// Algorithm selects the diff engine. The zero value selects
+	// HuntMcIlroy.
+	Algorithm Algorithm
+}
+
+// Uint64With is equivalent to Uint64, but uses the algorithm selected by
+// opts.
+func Uint64With(a, b []uint64, opts Options) []Op {
+	switch opts.Algorithm {
+	case Myers:
+		return myers(a, b)
+	default:
+		return Uint64(a, b)
+	}
+}
+
+// TextWith is equivalent to Text, but uses the algorithm selected by opts.
+func TextWith(a, b []byte, s SplitFunc, h HashFunc, opts Options) []TextDelta {
+	if s == nil {
+		s = SplitLines
+	}
+	if h == nil {
+		h = DefaultHash()
+	}
+	la, ha := tokenize(a, h, s)
+	lb, hb := tokenize(b, h, s)
+	d := Uint64With(ha, hb, opts)
+	var out []TextDelta
+	for _, op := range d {
+		δ := TextDelta{Op: op}
+		if op > OpA {
+			δ.Text = lb[0]
+			lb = lb[1:]
+		}
+		if op < OpB {
+			δ.Text = la[0]
+			la = la[1:]
+		}
+		out = append(out, δ)
+	}
+	return out
+}
+
+// LinesWith is equivalent to Lines, but uses the algorithm selected by opts.
+func LinesWith(a, b []byte, opts Options) []TextDelta {
+	return TextWith(a, b, nil, nil, opts)
+}
@@ -0,0 +1,204 @@
+package diff
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span is a byte range of a line, tagged with whether it was added, removed
+// or is common to both sides. See Refine.
+type Span struct {
+	Op   Op
+	Text []byte
+}
+
+// RefinedDelta is the result of refining a single TextDelta (or a pair of
+// adjacent OpA/OpB TextDeltas) down to sub-line granularity. A holds the
+// spans making up the old line, B the spans making up the new line; either
+// is nil if this delta has no corresponding side (a pure insertion or
+// deletion).
+type RefinedDelta struct {
+	A, B []Span
+}
+
+// Whitespace controls how Refine treats whitespace runs within a changed
+// line pair.
+type Whitespace int
+
+const (
+	// WhitespaceSplitOn treats whitespace runs as tokens like any other, so
+	// they can independently be marked as added, removed or unchanged. This
+	// is the default.
+	WhitespaceSplitOn Whitespace = iota
+	// WhitespaceIgnore treats all whitespace runs as equal to one another,
+	// regardless of their actual content, so that pure whitespace changes
+	// are never highlighted.
+	WhitespaceIgnore
+	// WhitespacePreserve folds each whitespace run into the token that
+	// follows it, so whitespace is never compared on its own.
+	WhitespacePreserve
+)
+
+// RefineOptions configures Refine.
+type RefineOptions struct {
+	// Split selects the token granularity used for the sub-line diff. The
+	// zero value uses SplitWords.
+	Split SplitFunc
+	// Whitespace selects how whitespace runs are treated. The zero value is
+	// WhitespaceSplitOn.
+	Whitespace Whitespace
+}
+
+// Refine takes deltas, as returned by Lines or Text, and refines each
+// changed block of lines down to word- or rune-level spans, so callers can
+// highlight exactly what changed within a line. A changed block is a
+// maximal run of consecutive non-OpEq deltas; the OpA and OpB lines within
+// it are paired off in order, first-removed with first-added, and so on.
+// Unpaired lines, when one side of a block has more lines than the other,
+// pass through as a single Span covering the whole line, as do OpEq lines.
+func Refine(deltas []TextDelta, opts RefineOptions) []RefinedDelta {
+	split := opts.Split
+	if split == nil {
+		split = SplitWords
+	}
+
+	out := make([]RefinedDelta, 0, len(deltas))
+	for i := 0; i < len(deltas); {
+		d := deltas[i]
+		if d.Op == OpEq {
+			out = append(out, RefinedDelta{
+				A: []Span{{Op: OpEq, Text: d.Text}},
+				B: []Span{{Op: OpEq, Text: d.Text}},
+			})
+			i++
+			continue
+		}
+
+		// Gather the whole contiguous block of changed lines. The
+		// Hunt–McIlroy implementation doesn't guarantee whether the OpA or
+		// the OpB run comes first within it, so we collect both regardless
+		// of their relative order and pair them up positionally.
+		var aRun, bRun []TextDelta
+		j := i
+		for j < len(deltas) && deltas[j].Op != OpEq {
+			if deltas[j].Op == OpA {
+				aRun = append(aRun, deltas[j])
+			} else {
+				bRun = append(bRun, deltas[j])
+			}
+			j++
+		}
+
+		n := len(aRun)
+		if len(bRun) < n {
+			n = len(bRun)
+		}
+		for p := 0; p < n; p++ {
+			a, b := refineLine(aRun[p].Text, bRun[p].Text, split, opts.Whitespace)
+			out = append(out, RefinedDelta{A: a, B: b})
+		}
+		for _, extra := range aRun[n:] {
+			out = append(out, RefinedDelta{A: []Span{{Op: OpA, Text: extra.Text}}})
+		}
+		for _, extra := range bRun[n:] {
+			out = append(out, RefinedDelta{B: []Span{{Op: OpB, Text: extra.Text}}})
+		}
+		i = j
+	}
+	return out
+}
+
+func refineLine(a, b []byte, split SplitFunc, ws Whitespace) (aSpans, bSpans []Span) {
+	h := DefaultHash()
+	if ws == WhitespaceIgnore {
+		h = whitespaceInsensitive(h)
+	}
+	if ws == WhitespacePreserve {
+		split = preserveWhitespace(split)
+	}
+
+	la, ha := tokenize(a, h, split)
+	lb, hb := tokenize(b, h, split)
+	for _, op := range Uint64(ha, hb) {
+		switch op {
+		case OpEq:
+			aSpans = appendSpan(aSpans, OpEq, la[0])
+			bSpans = appendSpan(bSpans, OpEq, lb[0])
+			la, lb = la[1:], lb[1:]
+		case OpA:
+			aSpans = appendSpan(aSpans, OpA, la[0])
+			la = la[1:]
+		case OpB:
+			bSpans = appendSpan(bSpans, OpB, lb[0])
+			lb = lb[1:]
+		}
+	}
+	return aSpans, bSpans
+}
+
+func appendSpan(spans []Span, op Op, text []byte) []Span {
+	if n := len(spans); n > 0 && spans[n-1].Op == op {
+		buf := make([]byte, 0, len(spans[n-1].Text)+len(text))
+		buf = append(buf, spans[n-1].Text...)
+		buf = append(buf, text...)
+		spans[n-1].Text = buf
+		return spans
+	}
+	return append(spans, Span{Op: op, Text: append([]byte(nil), text...)})
+}
+
+func whitespaceInsensitive(h HashFunc) HashFunc {
+	sentinel := h([]byte{' '})
+	return func(b []byte) uint64 {
+		if isAllSpace(b) {
+			return sentinel
+		}
+		return h(b)
+	}
+}
+
+func preserveWhitespace(split SplitFunc) SplitFunc {
+	return func(b []byte) (tok, skip int) {
+		tok, skip = split(b)
+		if tok+skip >= len(b) || !isAllSpace(b[:tok]) {
+			return tok, skip
+		}
+		tok2, skip2 := split(b[tok+skip:])
+		return tok + skip + tok2, skip2
+	}
+}
+
+func isAllSpace(b []byte) bool {
+	for _, r := range string(b) {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitWords splits b into alternating runs of whitespace and
+// non-whitespace bytes (as classified by unicode.IsSpace). It consumes no
+// bytes as separators, so the returned tokens concatenate back into the
+// original input.
+func SplitWords(b []byte) (tok, skip int) {
+	r, size := utf8.DecodeRune(b)
+	isSpace := unicode.IsSpace(r)
+	i := size
+	for i < len(b) {
+		r, size := utf8.DecodeRune(b[i:])
+		if unicode.IsSpace(r) != isSpace {
+			break
+		}
+		i += size
+	}
+	return i, 0
+}
+
+// SplitRunes splits b into one token per rune. It consumes no bytes as
+// separators, so the returned tokens concatenate back into the original
+// input.
+func SplitRunes(b []byte) (tok, skip int) {
+	_, size := utf8.DecodeRune(b)
+	return size, 0
+}
@@ -0,0 +1,87 @@
+package diff_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Merovius/diff"
+)
+
+func TestSplitWords(t *testing.T) {
+	tcs := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a b", []string{"a", " ", "b"}},
+		{"  ab  cd", []string{"  ", "ab", "  ", "cd"}},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			in := []byte(tc.in)
+			var got []string
+			for len(in) > 0 {
+				tok, skip := diff.SplitWords(in)
+				got = append(got, string(in[:tok]))
+				in = in[tok+skip:]
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("SplitWords(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func ExampleRefine() {
+	a := []byte("the quick brown fox\njumps over the lazy dog\n")
+	b := []byte("the quick red fox\njumps over the sleepy dog\n")
+	deltas := diff.Lines(a, b)
+	for _, δ := range diff.Refine(deltas, diff.RefineOptions{}) {
+		if len(δ.A) > 0 {
+			fmt.Print("-")
+			for _, s := range δ.A {
+				if s.Op != diff.OpEq {
+					fmt.Printf("[%s]", s.Text)
+				} else {
+					fmt.Printf("%s", s.Text)
+				}
+			}
+			fmt.Println()
+		}
+		if len(δ.B) > 0 {
+			fmt.Print("+")
+			for _, s := range δ.B {
+				if s.Op != diff.OpEq {
+					fmt.Printf("[%s]", s.Text)
+				} else {
+					fmt.Printf("%s", s.Text)
+				}
+			}
+			fmt.Println()
+		}
+	}
+	// Output:
+	// -the quick [brown] fox
+	// +the quick [red] fox
+	// -jumps over the [lazy] dog
+	// +jumps over the [sleepy] dog
+}
+
+func TestRefineWhitespace(t *testing.T) {
+	deltas := []diff.TextDelta{
+		{Op: diff.OpA, Text: []byte("a  b")},
+		{Op: diff.OpB, Text: []byte("a b")},
+	}
+
+	got := diff.Refine(deltas, diff.RefineOptions{Whitespace: diff.WhitespaceIgnore})
+	if len(got) != 1 {
+		t.Fatalf("Refine(...) = %d deltas, want 1", len(got))
+	}
+	for _, s := range got[0].A {
+		if s.Op != diff.OpEq {
+			t.Errorf("Refine(..., WhitespaceIgnore) marked %q as %v, want OpEq", s.Text, s.Op)
+		}
+	}
+}
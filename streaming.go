@@ -0,0 +1,223 @@
+package diff
+
+import (
+	"bufio"
+	"io"
+)
+
+// DifferOptions configures a Differ.
+type DifferOptions struct {
+	// MaxWindowTokens bounds how many tokens of each input are buffered at
+	// once. If no sync point (see Differ) is found within that many
+	// tokens, the window is cut there regardless. The default is 10000.
+	MaxWindowTokens int
+	// MinAnchorRun is the number of consecutive matching tokens required
+	// for a position to be used as a sync point. The default is 5.
+	MinAnchorRun int
+}
+
+func (o DifferOptions) maxWindowTokens() int {
+	if o.MaxWindowTokens <= 0 {
+		return 10000
+	}
+	return o.MaxWindowTokens
+}
+
+func (o DifferOptions) minAnchorRun() int {
+	if o.MinAnchorRun <= 0 {
+		return 5
+	}
+	return o.MinAnchorRun
+}
+
+// Differ computes a diff between two io.Readers incrementally, so that
+// neither input, nor the candidate list Uint64 builds internally, has to be
+// held in memory in full.
+//
+// Differ reads tokens from both sides into windows of up to
+// opts.MaxWindowTokens tokens each, and looks for a "sync point": a run of
+// opts.MinAnchorRun consecutive tokens with matching hashes, which is used
+// as a safe place to cut both inputs and diff the two bounded windows (with
+// the existing Uint64 engine) independently of everything before and after.
+// If no sync point is found before a window fills up, it is cut there
+// anyway, trading optimality for the memory bound; this is the same
+// tradeoff tools like rsync make when choosing sync points.
+type Differ struct {
+	a, b *tokenReader
+	hash HashFunc
+	opts DifferOptions
+}
+
+// NewDiffer creates a Differ reading tokens from a and b using split. If
+// hash is nil, DefaultHash() is used.
+func NewDiffer(a, b io.Reader, split SplitFunc, hash HashFunc, opts DifferOptions) *Differ {
+	if hash == nil {
+		hash = DefaultHash()
+	}
+	return &Differ{
+		a:    newTokenReader(a, split),
+		b:    newTokenReader(b, split),
+		hash: hash,
+		opts: opts,
+	}
+}
+
+// Run reads and diffs the two inputs incrementally, calling emit once for
+// every resulting TextDelta, in order. It stops and returns an error if
+// reading either input fails.
+func (d *Differ) Run(emit func(TextDelta)) error {
+	maxWin := d.opts.maxWindowTokens()
+	minRun := d.opts.minAnchorRun()
+
+	var aTok, bTok [][]byte
+	var aHash, bHash []uint64
+	aEOF, bEOF := false, false
+
+	fill := func() error {
+		for !aEOF && len(aTok) < maxWin {
+			tok, err := d.a.next()
+			if err == io.EOF {
+				aEOF = true
+				break
+			}
+			if err != nil {
+				return err
+			}
+			aTok = append(aTok, tok)
+			aHash = append(aHash, d.hash(tok))
+		}
+		for !bEOF && len(bTok) < maxWin {
+			tok, err := d.b.next()
+			if err == io.EOF {
+				bEOF = true
+				break
+			}
+			if err != nil {
+				return err
+			}
+			bTok = append(bTok, tok)
+			bHash = append(bHash, d.hash(tok))
+		}
+		return nil
+	}
+
+	flush := func(n, m int) {
+		ai, bi := 0, 0
+		for _, op := range Uint64(aHash[:n], bHash[:m]) {
+			switch op {
+			case OpEq:
+				emit(TextDelta{Op: OpEq, Text: aTok[ai]})
+				ai, bi = ai+1, bi+1
+			case OpA:
+				emit(TextDelta{Op: OpA, Text: aTok[ai]})
+				ai++
+			case OpB:
+				emit(TextDelta{Op: OpB, Text: bTok[bi]})
+				bi++
+			}
+		}
+		aTok, aHash = aTok[n:], aHash[n:]
+		bTok, bHash = bTok[m:], bHash[m:]
+	}
+
+	for {
+		if err := fill(); err != nil {
+			return err
+		}
+		if len(aHash) == 0 && len(bHash) == 0 {
+			return nil
+		}
+		if aEOF && bEOF {
+			flush(len(aHash), len(bHash))
+			return nil
+		}
+		if ai, bi, run, ok := findAnchor(aHash, bHash, minRun); ok {
+			flush(ai+run, bi+run)
+			continue
+		}
+		// No sync point in the current windows, and (since fill only
+		// returns early on EOF) at least one of them is as full as it's
+		// going to get without exceeding the bound: cut here.
+		flush(len(aHash), len(bHash))
+	}
+}
+
+// maxAnchorCandidates bounds how many equal-hash positions on the b side
+// findAnchor will try per token on the a side, so that a window full of
+// duplicate tokens (e.g. blank lines) doesn't make the search quadratic in
+// the number of duplicates.
+const maxAnchorCandidates = 8
+
+// findAnchor looks for the first run of at least minRun consecutive,
+// pairwise-equal hashes between aHash and bHash, scanning a in order. It
+// returns the run's start offsets and length, or ok == false if none was
+// found.
+func findAnchor(aHash, bHash []uint64, minRun int) (ai, bi, run int, ok bool) {
+	pos := make(map[uint64][]int, len(bHash))
+	for j, h := range bHash {
+		pos[h] = append(pos[h], j)
+	}
+	for i, h := range aHash {
+		cands := pos[h]
+		if len(cands) > maxAnchorCandidates {
+			cands = cands[:maxAnchorCandidates]
+		}
+		for _, j := range cands {
+			r := 0
+			for i+r < len(aHash) && j+r < len(bHash) && aHash[i+r] == bHash[j+r] {
+				r++
+			}
+			if r >= minRun {
+				return i, j, r, true
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// tokenReader incrementally splits an io.Reader into tokens using a
+// SplitFunc, reading only as much as is needed to disambiguate each token.
+type tokenReader struct {
+	r     *bufio.Reader
+	split SplitFunc
+	buf   []byte
+	eof   bool
+}
+
+func newTokenReader(r io.Reader, split SplitFunc) *tokenReader {
+	return &tokenReader{r: bufio.NewReaderSize(r, 64*1024), split: split}
+}
+
+// next returns the next token, or an io.EOF error once the input (and any
+// trailing partial token) has been fully consumed.
+func (t *tokenReader) next() ([]byte, error) {
+	for {
+		if len(t.buf) == 0 && t.eof {
+			return nil, io.EOF
+		}
+		if len(t.buf) > 0 {
+			tok, skip := t.split(t.buf)
+			// tok+skip < len(t.buf) means there is buffered data beyond
+			// this token, so it can't be an artifact of a not-yet-complete
+			// read; t.eof means no more data is coming at all, so whatever
+			// split returns is final either way.
+			if t.eof || tok+skip < len(t.buf) {
+				if tok == 0 && skip == 0 {
+					return nil, io.EOF
+				}
+				text := append([]byte(nil), t.buf[:tok]...)
+				t.buf = t.buf[tok+skip:]
+				return text, nil
+			}
+		}
+		chunk := make([]byte, 4096)
+		n, err := t.r.Read(chunk)
+		t.buf = append(t.buf, chunk[:n]...)
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			t.eof = true
+		}
+	}
+}
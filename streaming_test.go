@@ -0,0 +1,80 @@
+package diff_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Merovius/diff"
+)
+
+func runDiffer(t *testing.T, a, b string, opts diff.DifferOptions) []diff.TextDelta {
+	t.Helper()
+	d := diff.NewDiffer(strings.NewReader(a), strings.NewReader(b), diff.SplitLines, nil, opts)
+	var got []diff.TextDelta
+	if err := d.Run(func(δ diff.TextDelta) { got = append(got, δ) }); err != nil {
+		t.Fatalf("Run(...) = %v, want nil error", err)
+	}
+	return got
+}
+
+// reassemble concatenates the lines a Differ kept or added, the way Apply
+// would, to check that the streamed deltas actually describe b.
+func reassemble(deltas []diff.TextDelta) string {
+	var lines []string
+	for _, δ := range deltas {
+		if δ.Op != diff.OpA {
+			lines = append(lines, string(δ.Text))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDifferMatchesUint64(t *testing.T) {
+	tcs := []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"a\nb\nc\n", "a\nb\nc\n"},
+		{"a\nb\nc\n", "a\nx\nc\n"},
+		{"a\nb\nc\n", ""},
+		{"", "a\nb\nc\n"},
+		{"a\nb\nc\nd\ne\n", "a\nc\nd\ne\nf\n"},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			got := runDiffer(t, tc.a, tc.b, diff.DifferOptions{})
+			if gotB := reassemble(got); gotB != strings.TrimSuffix(tc.b, "\n") {
+				t.Errorf("Differ(%q, %q) reassembles to %q, want %q", tc.a, tc.b, gotB, strings.TrimSuffix(tc.b, "\n"))
+			}
+
+			want := diff.Lines([]byte(tc.a), []byte(tc.b))
+			if len(want) != len(got) {
+				t.Fatalf("Differ(%q, %q) = %d deltas, want %d", tc.a, tc.b, len(got), len(want))
+			}
+			for i := range want {
+				if want[i].Op != got[i].Op || !bytes.Equal(want[i].Text, got[i].Text) {
+					t.Errorf("Differ(%q, %q)[%d] = %+v, want %+v", tc.a, tc.b, i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDifferSmallWindow(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 200; i++ {
+		a = append(a, "line")
+		b = append(b, "line")
+	}
+	a[100] = "changed-a"
+	b[150] = "changed-b"
+
+	got := runDiffer(t, strings.Join(a, "\n")+"\n", strings.Join(b, "\n")+"\n", diff.DifferOptions{
+		MaxWindowTokens: 20,
+		MinAnchorRun:    3,
+	})
+	if gotB := reassemble(got); gotB != strings.Join(b, "\n") {
+		t.Errorf("Differ with a small window reassembled to a wrong result (len %d, want %d)", len(gotB), len(strings.Join(b, "\n")))
+	}
+}
@@ -0,0 +1,167 @@
+package unified
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Merovius/diff"
+)
+
+// ApplyOptions configures Apply.
+type ApplyOptions struct {
+	// Fuzz is the number of lines of context, at the start and end of a
+	// hunk, that Apply is allowed to ignore when matching it against a,
+	// mirroring patch(1)'s -F flag. The zero value requires every context
+	// and removed line in a hunk to match exactly.
+	Fuzz int
+}
+
+// HunkResult reports whether a single hunk applied successfully. If not, Err
+// explains why.
+type HunkResult struct {
+	Applied bool
+	Err     error
+}
+
+// Apply applies patch, as produced by Format or Diff, to a, returning the
+// patched result along with one HunkResult per hunk in patch, in the same
+// order. Hunks are applied independently and in order; a hunk that fails to
+// match is skipped (recorded in its HunkResult, not returned as an error)
+// and a is left unchanged at that point, so a single bad hunk doesn't
+// prevent the rest of the patch from applying.
+//
+// Apply first tries to match each hunk's old-side lines against a exactly,
+// at the line offset implied by the hunk header (adjusted for the net line
+// count change of hunks already applied). If that fails and opts.Fuzz is
+// greater than 0, it retries while ignoring up to opts.Fuzz lines of
+// context at each end of the hunk.
+//
+// Unlike patch(1), Apply never searches nearby line offsets for a hunk that
+// doesn't match at its header-implied position (with or without fuzz) — a
+// hunk either matches there or it's reported as failed in its HunkResult.
+// This lives here, rather than as diff.ApplyUnified operating on
+// unified.Hunk, because package diff can't import this package without a
+// cycle (this package already imports diff for Op and TextDelta); diff.Apply
+// and diff.ApplyUint64 cover the non-unified-diff cases.
+func Apply(a []byte, patch []byte, opts ApplyOptions) ([]byte, []HunkResult, error) {
+	hunks, err := Parse(patch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unified: %w", err)
+	}
+
+	oldHasNewline := endsInNewline(a)
+	var lines [][]byte
+	if len(a) > 0 {
+		lines = bytes.Split(a, []byte("\n"))
+		if oldHasNewline {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	origOldLines := len(lines)
+
+	results := make([]HunkResult, len(hunks))
+	shift := 0
+	for i, h := range hunks {
+		patched, ok, err := applyHunk(lines, h, shift, opts.Fuzz)
+		if err != nil {
+			results[i] = HunkResult{Err: err}
+			continue
+		}
+		lines = patched
+		shift += h.NewCount - h.OldCount
+		results[i] = HunkResult{Applied: ok}
+	}
+
+	// The result's trailing newline defaults to a's, since any content past
+	// the last applied hunk is carried over from a verbatim. But if the
+	// last hunk both applied and reaches all the way to a's last line, it
+	// determines the new side's true last line, and the patch's own record
+	// of whether that line has a trailing newline (via Line.NoNewline, set
+	// by Parse from the "\ No newline at end of file" marker) takes
+	// precedence over a's.
+	trailingNewline := oldHasNewline
+	if n := len(hunks); n > 0 {
+		h := hunks[n-1]
+		if results[n-1].Applied && reachesOldEOF(h, origOldLines) {
+			for li := len(h.Lines) - 1; li >= 0; li-- {
+				if h.Lines[li].Op != diff.OpA {
+					trailingNewline = !h.Lines[li].NoNewline
+					break
+				}
+			}
+		}
+	}
+
+	out := bytes.Join(lines, []byte("\n"))
+	if trailingNewline && len(lines) > 0 {
+		out = append(out, '\n')
+	}
+	return out, results, nil
+}
+
+// applyHunk tries to splice h into lines at the offset implied by its
+// header (adjusted by shift, the net line count change of hunks already
+// applied), loosening the match by up to fuzz lines of context at each end
+// if necessary.
+func applyHunk(lines [][]byte, h Hunk, shift, fuzz int) ([][]byte, bool, error) {
+	var oldLines, newLines [][]byte
+	for _, l := range h.Lines {
+		if l.Op != diff.OpB {
+			oldLines = append(oldLines, l.Text)
+		}
+		if l.Op != diff.OpA {
+			newLines = append(newLines, l.Text)
+		}
+	}
+
+	start := h.OldStart - 1 + shift
+	if h.OldCount == 0 {
+		start = h.OldStart + shift
+	}
+
+	if start < 0 || start+len(oldLines) > len(lines) {
+		return lines, false, fmt.Errorf("unified: hunk at line %d is out of range for a", h.OldStart)
+	}
+
+	for f := 0; f <= fuzz && 2*f <= len(oldLines) && 2*f <= len(newLines); f++ {
+		want := oldLines[f : len(oldLines)-f]
+		at := start + f
+		if !linesEqual(lines[at:at+len(want)], want) {
+			continue
+		}
+		// The f lines of context ignored at each end aren't necessarily
+		// exactly what the hunk recorded (that's the point of fuzz), so
+		// keep a's own text there instead of overwriting it with the
+		// hunk's.
+		out := make([][]byte, 0, len(lines)-len(oldLines)+len(newLines))
+		out = append(out, lines[:start+f]...)
+		out = append(out, newLines[f:len(newLines)-f]...)
+		out = append(out, lines[start+len(oldLines)-f:]...)
+		return out, true, nil
+	}
+	return lines, false, fmt.Errorf("unified: hunk at line %d does not match a (even with fuzz %d)", h.OldStart, fuzz)
+}
+
+// reachesOldEOF reports whether h's old-side range ends at origOldLines (the
+// total number of lines on the old side), matching the convention Hunks
+// uses for pure insertions: when OldCount is 0, OldStart is the 0-based line
+// the insertion happens before (not 1-based), so the usual
+// OldStart+OldCount-1 last-line-index formula doesn't apply.
+func reachesOldEOF(h Hunk, origOldLines int) bool {
+	if h.OldCount == 0 {
+		return h.OldStart == origOldLines
+	}
+	return h.OldStart+h.OldCount-1 == origOldLines
+}
+
+func linesEqual(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package unified_test
+
+import (
+	"testing"
+
+	"github.com/Merovius/diff/unified"
+)
+
+func TestApplyRoundTrip(t *testing.T) {
+	tcs := []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"a\n", "a\n"},
+		{"a\nb\nc\n", "a\nx\nc\n"},
+		{"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n", "a\nb\nC\nd\ne\nf\ng\nH\ni\nj\n"},
+		{"a\nb\nc\n", ""},
+		{"", "a\nb\nc\n"},
+		{"a\nb", "a\nc"},
+		{"x\n", "x\ny"},
+		{"x\ny", "x\n"},
+		{"x", "x\n"},
+		{"", "x\ny"},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			a, b := []byte(tc.a), []byte(tc.b)
+			patch := unified.Diff("a", "b", a, b, unified.Options{Context: 1})
+			got, results, err := unified.Apply(a, patch, unified.ApplyOptions{})
+			if err != nil {
+				t.Fatalf("Apply(%q, %q) = _, _, %v, want nil error", a, patch, err)
+			}
+			for i, r := range results {
+				if !r.Applied {
+					t.Errorf("hunk %d failed to apply: %v", i, r.Err)
+				}
+			}
+			if string(got) != tc.b {
+				t.Errorf("Apply(%q, Diff(a, b)) = %q, want %q", a, got, tc.b)
+			}
+		})
+	}
+}
+
+func TestApplyFuzz(t *testing.T) {
+	a := []byte("1\n2\n3\nhello\n5\n6\n7\n")
+	b := []byte("1\n2\n3\nworld\n5\n6\n7\n")
+	patch := unified.Diff("a", "b", a, b, unified.Options{Context: 2})
+
+	// Simulate drift: the context around the change no longer matches
+	// exactly, but the hunk should still apply with enough fuzz.
+	drifted := []byte("1\n2\nTHREE\nhello\n5\nSIX\n7\n")
+
+	if _, results, _ := unified.Apply(drifted, patch, unified.ApplyOptions{}); results[0].Applied {
+		t.Fatal("Apply with no fuzz applied a hunk whose context doesn't match, want failure")
+	}
+
+	got, results, err := unified.Apply(drifted, patch, unified.ApplyOptions{Fuzz: 2})
+	if err != nil {
+		t.Fatalf("Apply(..., Fuzz: 2) = _, _, %v, want nil error", err)
+	}
+	if !results[0].Applied {
+		t.Fatalf("Apply(..., Fuzz: 2) failed to apply: %v", results[0].Err)
+	}
+	want := "1\n2\nTHREE\nworld\n5\nSIX\n7\n"
+	if string(got) != want {
+		t.Errorf("Apply(..., Fuzz: 2) = %q, want %q", got, want)
+	}
+}
+
+func TestApplyMismatch(t *testing.T) {
+	a := []byte("a\nb\nc\n")
+	b := []byte("a\nx\nc\n")
+	patch := unified.Diff("a", "b", a, b, unified.Options{Context: 1})
+
+	got, results, err := unified.Apply([]byte("a\nz\nc\n"), patch, unified.ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Apply(...) = _, _, %v, want nil error", err)
+	}
+	if results[0].Applied {
+		t.Fatal("Apply applied a hunk against mismatched content, want failure")
+	}
+	if string(got) != "a\nz\nc\n" {
+		t.Errorf("Apply left a changed on a failed hunk: got %q", got)
+	}
+}
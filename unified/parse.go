@@ -0,0 +1,111 @@
+package unified
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+
+	"github.com/Merovius/diff"
+)
+
+// Parse parses a unified diff, as produced by Format or Diff, into its
+// constituent hunks. The "--- "/"+++ " file headers, if present, are skipped.
+func Parse(patch []byte) ([]Hunk, error) {
+	s := bufio.NewScanner(bytes.NewReader(patch))
+	s.Buffer(nil, 1<<20)
+
+	var (
+		hunks []Hunk
+		cur   *Hunk
+		line  int
+	)
+	for s.Scan() {
+		line++
+		b := s.Bytes()
+		switch {
+		case bytes.HasPrefix(b, []byte("--- ")), bytes.HasPrefix(b, []byte("+++ ")):
+			if cur != nil {
+				return nil, fmt.Errorf("unified: line %d: file header after hunks started", line)
+			}
+		case bytes.HasPrefix(b, []byte("@@ ")):
+			h, err := parseHunkHeader(b)
+			if err != nil {
+				return nil, fmt.Errorf("unified: line %d: %w", line, err)
+			}
+			if cur != nil {
+				hunks = append(hunks, *cur)
+			}
+			cur = &h
+		case bytes.HasPrefix(b, []byte(noNewlineMarker)):
+			if cur == nil || len(cur.Lines) == 0 {
+				return nil, fmt.Errorf("unified: line %d: no-newline marker without a preceding line", line)
+			}
+			cur.Lines[len(cur.Lines)-1].NoNewline = true
+		case cur == nil:
+			return nil, fmt.Errorf("unified: line %d: content line outside of a hunk", line)
+		default:
+			if len(b) == 0 {
+				return nil, fmt.Errorf("unified: line %d: empty line in hunk", line)
+			}
+			op, err := opFromPrefix(b[0])
+			if err != nil {
+				return nil, fmt.Errorf("unified: line %d: %w", line, err)
+			}
+			cur.Lines = append(cur.Lines, Line{Op: op, Text: append([]byte(nil), b[1:]...)})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		hunks = append(hunks, *cur)
+	}
+	return hunks, nil
+}
+
+func opFromPrefix(b byte) (diff.Op, error) {
+	switch b {
+	case '-':
+		return diff.OpA, nil
+	case '+':
+		return diff.OpB, nil
+	case ' ':
+		return diff.OpEq, nil
+	default:
+		return 0, fmt.Errorf("invalid line prefix %q", b)
+	}
+}
+
+func parseHunkHeader(b []byte) (Hunk, error) {
+	fields := bytes.Fields(b)
+	if len(fields) < 4 || !bytes.Equal(fields[0], []byte("@@")) || fields[len(fields)-1][0] != '@' {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q", b)
+	}
+	var h Hunk
+	var err error
+	if h.OldStart, h.OldCount, err = parseRange(fields[1], '-'); err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", b, err)
+	}
+	if h.NewStart, h.NewCount, err = parseRange(fields[2], '+'); err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", b, err)
+	}
+	return h, nil
+}
+
+func parseRange(f []byte, sign byte) (start, count int, err error) {
+	if len(f) == 0 || f[0] != sign {
+		return 0, 0, fmt.Errorf("range %q doesn't start with %q", f, sign)
+	}
+	f = f[1:]
+	count = 1
+	if i := bytes.IndexByte(f, ','); i >= 0 {
+		if _, err := fmt.Sscanf(string(f[i+1:]), "%d", &count); err != nil {
+			return 0, 0, fmt.Errorf("invalid count in range %q: %w", f, err)
+		}
+		f = f[:i]
+	}
+	if _, err := fmt.Sscanf(string(f), "%d", &start); err != nil {
+		return 0, 0, fmt.Errorf("invalid start in range %q: %w", f, err)
+	}
+	return start, count, nil
+}
@@ -0,0 +1,303 @@
+// Package unified renders and parses the unified diff format, as produced by
+// diff(1) with the -u flag (and consumed by patch(1), git and most code
+// review tools).
+package unified
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Merovius/diff"
+)
+
+// DefaultContext is the number of context lines used by Format and Hunks if
+// Options.Context is zero.
+const DefaultContext = 3
+
+// Options controls the rendering of a unified diff.
+type Options struct {
+	// Context is the number of unchanged lines to include around each
+	// change. If zero, DefaultContext is used.
+	Context int
+}
+
+func (o Options) context() int {
+	if o.Context <= 0 {
+		return DefaultContext
+	}
+	return o.Context
+}
+
+// Line is a single line of a Hunk.
+type Line struct {
+	Op   diff.Op
+	Text []byte
+
+	// NoNewline records that this line, as it appeared in its original
+	// file, is not followed by a newline. It is only ever set on a line
+	// that is genuinely the last line of its side (old for OpA/OpEq, new
+	// for OpEq/OpB); Format and Parse represent it as a trailing
+	// "\ No newline at end of file" marker.
+	NoNewline bool
+}
+
+// Hunk is a contiguous group of changed (and some context) lines, as
+// delimited by a "@@ -l,s +l,s @@" header in a unified diff.
+//
+// OldStart and NewStart are 1-based. If OldCount (resp. NewCount) is 0,
+// OldStart (resp. NewStart) is the line before the hunk applies, per the
+// unified diff convention for insertions into (or deletions from) an empty
+// file.
+type Hunk struct {
+	OldStart, OldCount int
+	NewStart, NewCount int
+	Lines              []Line
+}
+
+// Hunks groups deltas (as returned by diff.Text or diff.Lines) into hunks,
+// each padded with up to opts.Context lines of unchanged context.
+//
+// Changes that are more than 2*opts.Context lines apart are put into separate
+// hunks; closer changes are merged into a single hunk.
+func Hunks(deltas []diff.TextDelta, opts Options) []Hunk {
+	context := opts.context()
+
+	oldAt := make([]int, len(deltas)+1)
+	newAt := make([]int, len(deltas)+1)
+	for i, d := range deltas {
+		oldAt[i+1], newAt[i+1] = oldAt[i], newAt[i]
+		if d.Op != diff.OpB {
+			oldAt[i+1]++
+		}
+		if d.Op != diff.OpA {
+			newAt[i+1]++
+		}
+	}
+
+	var hunks []Hunk
+	n := len(deltas)
+	i := 0
+	for i < n {
+		for i < n && deltas[i].Op == diff.OpEq {
+			i++
+		}
+		if i >= n {
+			break
+		}
+
+		lo := i - context
+		if lo < 0 {
+			lo = 0
+		}
+
+		end, j, run := i, i, n
+		for j < n {
+			if deltas[j].Op != diff.OpEq {
+				end = j + 1
+				j++
+				continue
+			}
+			k := j
+			for k < n && deltas[k].Op == diff.OpEq {
+				k++
+			}
+			if k-j > 2*context {
+				run = k
+				break
+			}
+			if k == n {
+				run = k
+				break
+			}
+			j = k
+		}
+
+		hi := end + context
+		if hi > run {
+			hi = run
+		}
+
+		hunks = append(hunks, newHunk(deltas[lo:hi], oldAt[lo], newAt[lo]))
+		i = hi
+	}
+	return hunks
+}
+
+func newHunk(deltas []diff.TextDelta, oldAt, newAt int) Hunk {
+	deltas = reorderSubstitutions(deltas)
+	h := Hunk{OldStart: oldAt + 1, NewStart: newAt + 1}
+	h.Lines = make([]Line, len(deltas))
+	for i, d := range deltas {
+		h.Lines[i] = Line{Op: d.Op, Text: d.Text}
+		if d.Op != diff.OpB {
+			h.OldCount++
+		}
+		if d.Op != diff.OpA {
+			h.NewCount++
+		}
+	}
+	if h.OldCount == 0 {
+		h.OldStart = oldAt
+	}
+	if h.NewCount == 0 {
+		h.NewStart = newAt
+	}
+	return h
+}
+
+// reorderSubstitutions returns deltas with every maximal run of consecutive
+// non-OpEq deltas rearranged so that all its OpA (removed) lines come
+// before all its OpB (added) lines, each preserving their relative order.
+// The diff engine doesn't guarantee either order, but most diff tools
+// (e.g. git) always show removals before additions within a substitution,
+// and consumers may rely on that.
+func reorderSubstitutions(deltas []diff.TextDelta) []diff.TextDelta {
+	out := make([]diff.TextDelta, 0, len(deltas))
+	for i := 0; i < len(deltas); {
+		if deltas[i].Op == diff.OpEq {
+			out = append(out, deltas[i])
+			i++
+			continue
+		}
+		var as, bs []diff.TextDelta
+		j := i
+		for j < len(deltas) && deltas[j].Op != diff.OpEq {
+			if deltas[j].Op == diff.OpA {
+				as = append(as, deltas[j])
+			} else {
+				bs = append(bs, deltas[j])
+			}
+			j++
+		}
+		out = append(out, as...)
+		out = append(out, bs...)
+		i = j
+	}
+	return out
+}
+
+// Format renders deltas (as returned by diff.Text or diff.Lines) as a unified
+// diff, with oldName and newName used in the "--- "/"+++ " headers.
+//
+// Format assumes both inputs end in a trailing newline. Use Diff to correctly
+// handle inputs that don't.
+func Format(oldName, newName string, deltas []diff.TextDelta, opts Options) []byte {
+	return format(oldName, newName, Hunks(deltas, opts))
+}
+
+// Diff calculates a unified diff between a and b, splitting and hashing them
+// using diff.Lines.
+//
+// If a and b differ only in whether their very last line is followed by a
+// newline, and that line is otherwise unchanged, the change is not
+// representable in a line-based diff unless special-cased: both sides
+// tokenize to the same lines, so there is nothing for Hunks to group into a
+// hunk. Diff detects exactly that case and synthesizes a one-line
+// substitution for it (mirroring what tools like git show), but a trailing
+// newline change that coincides with other edits far enough from any hunk's
+// context window (more than opts.Context lines away) can still go
+// unrepresented, same as any other change outside of a hunk's reach.
+func Diff(oldName, newName string, a, b []byte, opts Options) []byte {
+	deltas := diff.Lines(a, b)
+	deltas = splitTrailingNewlineChange(deltas, a, b)
+	oldTotal, newTotal := 0, 0
+	for _, d := range deltas {
+		if d.Op != diff.OpB {
+			oldTotal++
+		}
+		if d.Op != diff.OpA {
+			newTotal++
+		}
+	}
+	hunks := Hunks(deltas, opts)
+	markNoNewline(hunks, oldTotal, newTotal, !endsInNewline(a), !endsInNewline(b))
+	return format(oldName, newName, hunks)
+}
+
+// splitTrailingNewlineChange turns deltas' final delta, if it's an OpEq
+// whose trailing newline status differs between a and b, into an OpA/OpB
+// pair over the same text. Otherwise it's unrepresentable: the only
+// difference between a and b would be invisible to Hunks, which only ever
+// groups around non-equal deltas.
+func splitTrailingNewlineChange(deltas []diff.TextDelta, a, b []byte) []diff.TextDelta {
+	if len(deltas) == 0 || endsInNewline(a) == endsInNewline(b) {
+		return deltas
+	}
+	for _, d := range deltas {
+		if d.Op != diff.OpEq {
+			return deltas
+		}
+	}
+	last := deltas[len(deltas)-1]
+	out := append([]diff.TextDelta(nil), deltas[:len(deltas)-1]...)
+	return append(out, diff.TextDelta{Op: diff.OpA, Text: last.Text}, diff.TextDelta{Op: diff.OpB, Text: last.Text})
+}
+
+func endsInNewline(b []byte) bool {
+	return len(b) == 0 || b[len(b)-1] == '\n'
+}
+
+const noNewlineMarker = "\\ No newline at end of file"
+
+// markNoNewline sets NoNewline on the last hunk's final old-side and/or
+// new-side Line, if noOld (resp. noNew) is set and that line is genuinely
+// the last of its side. oldTotal and newTotal are the number of old/new
+// lines across the whole diff (not just the shown hunks), since Hunks may
+// trim the final hunk's trailing context well before a file's actual end;
+// the flag must only ever be set on a line that is genuinely the last of
+// its side, not merely the last line the final hunk happens to show.
+func markNoNewline(hunks []Hunk, oldTotal, newTotal int, noOld, noNew bool) {
+	if len(hunks) == 0 || (!noOld && !noNew) {
+		return
+	}
+	h := &hunks[len(hunks)-1]
+	lastOld, lastNew := -1, -1
+	for li, l := range h.Lines {
+		if l.Op != diff.OpB {
+			lastOld = li
+		}
+		if l.Op != diff.OpA {
+			lastNew = li
+		}
+	}
+	if noOld && lastOld >= 0 && h.OldStart+h.OldCount-1 == oldTotal {
+		h.Lines[lastOld].NoNewline = true
+	}
+	if noNew && lastNew >= 0 && h.NewStart+h.NewCount-1 == newTotal {
+		h.Lines[lastNew].NoNewline = true
+	}
+}
+
+// format renders hunks as a unified diff, printing noNewlineMarker after any
+// line with NoNewline set.
+func format(oldName, newName string, hunks []Hunk) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n", oldName)
+	fmt.Fprintf(&buf, "+++ %s\n", newName)
+	for _, h := range hunks {
+		fmt.Fprintf(&buf, "@@ %s %s @@\n", rangeStr(h.OldStart, h.OldCount, '-'), rangeStr(h.NewStart, h.NewCount, '+'))
+		for _, l := range h.Lines {
+			switch l.Op {
+			case diff.OpA:
+				buf.WriteByte('-')
+			case diff.OpEq:
+				buf.WriteByte(' ')
+			case diff.OpB:
+				buf.WriteByte('+')
+			}
+			buf.Write(l.Text)
+			buf.WriteByte('\n')
+			if l.NoNewline {
+				fmt.Fprintln(&buf, noNewlineMarker)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func rangeStr(start, count int, sign byte) string {
+	if count == 1 {
+		return fmt.Sprintf("%c%d", sign, start)
+	}
+	return fmt.Sprintf("%c%d,%d", sign, start, count)
+}
@@ -0,0 +1,135 @@
+package unified_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Merovius/diff"
+	"github.com/Merovius/diff/unified"
+)
+
+func ExampleDiff() {
+	a := []byte("a\nb\nc\nd\nf\ng\nh\nj\nq\nz\n")
+	b := []byte("a\nb\nc\nd\ne\nf\ng\ni\nj\nk\nr\nx\ny\nz\n")
+	fmt.Print(string(unified.Diff("old", "new", a, b, unified.Options{Context: 1})))
+	// Output:
+	// --- old
+	// +++ new
+	// @@ -4,7 +4,11 @@
+	//  d
+	// +e
+	//  f
+	//  g
+	// -h
+	// +i
+	//  j
+	// -q
+	// +k
+	// +r
+	// +x
+	// +y
+	//  z
+}
+
+func TestFormatNoTrailingNewline(t *testing.T) {
+	a := []byte("a\nb")
+	b := []byte("a\nc")
+	got := unified.Diff("old", "new", a, b, unified.Options{})
+	want := "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"
+	if string(got) != want {
+		t.Errorf("Diff(%q, %q) = %q, want %q", a, b, got, want)
+	}
+}
+
+func TestFormatSubstitutionOrder(t *testing.T) {
+	// Within a substitution, removed lines must always render before added
+	// ones, regardless of the order the underlying diff engine produced
+	// them in, matching the convention tools like git use.
+	a := []byte("a\nOLD\nb\n")
+	b := []byte("a\nNEW\nb\n")
+	got := unified.Diff("old", "new", a, b, unified.Options{})
+	want := "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-OLD\n+NEW\n b\n"
+	if string(got) != want {
+		t.Errorf("Diff(%q, %q) = %q, want %q", a, b, got, want)
+	}
+}
+
+func TestFormatNoNewlineFarFromChange(t *testing.T) {
+	// The change (and thus the only hunk) is at line 1, but the file's last
+	// line, which lacks a trailing newline, is well beyond the default
+	// 3-line context window: the hunk doesn't reach EOF, so no marker
+	// should be printed at all.
+	var lines []string
+	for i := 1; i <= 12; i++ {
+		lines = append(lines, fmt.Sprintf("line%d", i))
+	}
+	a := []byte(strings.Join(lines, "\n")) // no trailing newline
+	lines[0] = "changed"
+	b := []byte(strings.Join(lines, "\n"))
+
+	got := unified.Diff("old", "new", a, b, unified.Options{})
+	if bytes.Contains(got, []byte(`No newline`)) {
+		t.Errorf("Diff(...) = %q, contains a no-newline marker for a hunk that doesn't reach EOF", got)
+	}
+
+	hunks, err := unified.Parse(got)
+	if err != nil {
+		t.Fatalf("Parse(%q) = _, %v", got, err)
+	}
+	if len(hunks) != 1 {
+		t.Fatalf("Diff(...) produced %d hunks, want 1", len(hunks))
+	}
+	if last := hunks[0]; last.OldStart+last.OldCount-1 == 12 {
+		t.Fatalf("test is unsound: hunk unexpectedly reaches the file's last line")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tcs := []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"a\n", "a\n"},
+		{"a\nb\nc\n", "a\nx\nc\n"},
+		{"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n", "a\nb\nC\nd\ne\nf\ng\nH\ni\nj\n"},
+		{"a\nb\nc\n", ""},
+		{"", "a\nb\nc\n"},
+	}
+	for _, tc := range tcs {
+		t.Run("", func(t *testing.T) {
+			patch := unified.Diff("a", "b", []byte(tc.a), []byte(tc.b), unified.Options{Context: 1})
+			hunks, err := unified.Parse(patch)
+			if err != nil {
+				t.Fatalf("Parse(%q) = _, %v", patch, err)
+			}
+			deltas := diff.Lines([]byte(tc.a), []byte(tc.b))
+			want := unified.Hunks(deltas, unified.Options{Context: 1})
+			if !hunksEqual(hunks, want) {
+				t.Fatalf("Parse(Diff(...)) = %+v, want %+v", hunks, want)
+			}
+		})
+	}
+}
+
+func hunksEqual(a, b []unified.Hunk) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].OldStart != b[i].OldStart || a[i].OldCount != b[i].OldCount ||
+			a[i].NewStart != b[i].NewStart || a[i].NewCount != b[i].NewCount {
+			return false
+		}
+		if len(a[i].Lines) != len(b[i].Lines) {
+			return false
+		}
+		for j := range a[i].Lines {
+			if a[i].Lines[j].Op != b[i].Lines[j].Op || !bytes.Equal(a[i].Lines[j].Text, b[i].Lines[j].Text) {
+				return false
+			}
+		}
+	}
+	return true
+}